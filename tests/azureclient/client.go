@@ -0,0 +1,141 @@
+// Package azureclient provides a single "Config -> NewClient" entry point
+// for Terratest helpers that need to make live Azure calls, mirroring the
+// pattern the legacy Azure provider used for picking an auth mode. It lets
+// CI authenticate via GitHub OIDC workload identity federation without a
+// static secret, while local runs keep the `az login` experience.
+package azureclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+)
+
+// AuthMode records which credential a Client ended up using, mainly so
+// tests can log it for debuggability.
+type AuthMode string
+
+const (
+	AuthModeClientSecret     AuthMode = "client-secret"
+	AuthModeWorkloadIdentity AuthMode = "workload-identity"
+	AuthModePublishSettings  AuthMode = "publish-settings"
+	AuthModeCLI              AuthMode = "cli"
+)
+
+// Config selects how a Client authenticates. Exactly one of the auth inputs
+// should be set; NewClient picks the most specific one present and falls
+// back to `az login` CLI credentials when none are.
+type Config struct {
+	SubscriptionID string
+	TenantID       string
+
+	// ClientID/ClientSecret select service-principal client-secret auth.
+	ClientID     string
+	ClientSecret string
+
+	// FederatedTokenFile selects workload-identity federated token auth
+	// (e.g. GitHub OIDC, AKS workload identity). ClientID must also be set.
+	FederatedTokenFile string
+
+	// PublishSettingsFile selects the legacy Azure Service Management
+	// publish-settings file. Kept for parity with the legacy provider's
+	// Config shape; NewClient rejects it since ARM has no publish-settings
+	// credential type, see NewClient's doc comment.
+	PublishSettingsFile string
+}
+
+// ConfigFromEnvironment builds a Config from the same environment variables
+// the azurerm provider and Azure CLI use, so tests and CI need no
+// Terratest-specific wiring:
+//
+//   - ARM_SUBSCRIPTION_ID, ARM_TENANT_ID
+//   - ARM_CLIENT_ID, ARM_CLIENT_SECRET            (client-secret)
+//   - ARM_CLIENT_ID, AZURE_FEDERATED_TOKEN_FILE   (workload identity / OIDC)
+//   - AZURE_PUBLISH_SETTINGS_FILE                 (legacy, rejected)
+//
+// With none of those set, NewClient falls back to `az login` CLI auth.
+func ConfigFromEnvironment() Config {
+	return Config{
+		SubscriptionID:      os.Getenv("ARM_SUBSCRIPTION_ID"),
+		TenantID:            os.Getenv("ARM_TENANT_ID"),
+		ClientID:            os.Getenv("ARM_CLIENT_ID"),
+		ClientSecret:        os.Getenv("ARM_CLIENT_SECRET"),
+		FederatedTokenFile:  os.Getenv("AZURE_FEDERATED_TOKEN_FILE"),
+		PublishSettingsFile: os.Getenv("AZURE_PUBLISH_SETTINGS_FILE"),
+	}
+}
+
+// Client wraps the azidentity credential NewClient picked, plus the
+// subscription it should operate against.
+type Client struct {
+	SubscriptionID string
+	AuthMode       AuthMode
+	credential     azcore.TokenCredential
+}
+
+// NewClient picks an azidentity credential from cfg, preferring (in order)
+// service-principal client-secret, workload-identity federated token, and
+// falling back to `az login` CLI credentials. PublishSettingsFile is
+// rejected outright: ARM is an OAuth-only control plane, so there is no
+// azidentity credential type that can authenticate with an ASM
+// publish-settings file against it.
+func (cfg Config) NewClient() (*Client, error) {
+	switch {
+	case cfg.ClientID != "" && cfg.ClientSecret != "":
+		cred, err := azidentity.NewClientSecretCredential(cfg.TenantID, cfg.ClientID, cfg.ClientSecret, nil)
+		if err != nil {
+			return nil, fmt.Errorf("azureclient: client-secret credential: %w", err)
+		}
+		return &Client{SubscriptionID: cfg.SubscriptionID, AuthMode: AuthModeClientSecret, credential: cred}, nil
+
+	case cfg.FederatedTokenFile != "":
+		cred, err := azidentity.NewWorkloadIdentityCredential(&azidentity.WorkloadIdentityCredentialOptions{
+			TenantID:      cfg.TenantID,
+			ClientID:      cfg.ClientID,
+			TokenFilePath: cfg.FederatedTokenFile,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("azureclient: workload-identity credential: %w", err)
+		}
+		return &Client{SubscriptionID: cfg.SubscriptionID, AuthMode: AuthModeWorkloadIdentity, credential: cred}, nil
+
+	case cfg.PublishSettingsFile != "":
+		return nil, errors.New("azureclient: publish-settings auth is not supported against Azure Resource Manager; " +
+			"use a service principal, workload identity federation, or az login instead")
+
+	default:
+		cred, err := azidentity.NewAzureCLICredential(nil)
+		if err != nil {
+			return nil, fmt.Errorf("azureclient: az login CLI credential: %w", err)
+		}
+		return &Client{SubscriptionID: cfg.SubscriptionID, AuthMode: AuthModeCLI, credential: cred}, nil
+	}
+}
+
+// ResourceExists reports whether the ARM resource at resourceID exists,
+// using the generic resources client so callers don't need a type-specific
+// SDK client for every resource kind Terratest helpers look up (subnets,
+// NSGs, peerings, ...).
+func (c *Client) ResourceExists(ctx context.Context, resourceID, apiVersion string) (bool, error) {
+	client, err := armresources.NewClient(c.SubscriptionID, c.credential, nil)
+	if err != nil {
+		return false, fmt.Errorf("azureclient: creating resources client: %w", err)
+	}
+
+	_, err = client.GetByID(ctx, resourceID, apiVersion, nil)
+	if err == nil {
+		return true, nil
+	}
+
+	var respErr *azcore.ResponseError
+	if errors.As(err, &respErr) && respErr.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	return false, fmt.Errorf("azureclient: getting %s: %w", resourceID, err)
+}
@@ -0,0 +1,105 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"time"
+)
+
+// volterraOpenAPIURLEnv overrides the upstream schema endpoint, mainly so CI
+// can point at a pinned mirror instead of the live F5 Distributed Cloud docs
+// site.
+const volterraOpenAPIURLEnv = "F5XC_OPENAPI_URL"
+
+// defaultVolterraOpenAPIURL is the live F5 Distributed Cloud OpenAPI document
+// that describes the public `ves.io.schema.views.azure_vnet_site` object,
+// which the `volterra_azure_vnet_site` Terraform resource maps onto.
+const defaultVolterraOpenAPIURL = "https://docs.cloud.f5.com/docs-v2/openapi/ves.io.schema.views.azure_vnet_site.swagger.json"
+
+// pinnedSiteSchemaFile is a committed snapshot of the upstream schema, taken
+// the last time someone confirmed it against docs.cloud.f5.com. Offline runs
+// serve this from a mock server instead of the real site. It is sourced
+// independently of moduleAssumedSchema() below -- e.g. it may list
+// ce_site_size values upstream supports that our modules don't use yet --
+// so the drift check in schema_contract_test.go is comparing two genuinely
+// separate sources, not the mock against itself.
+const pinnedSiteSchemaFile = "testdata/volterra_azure_vnet_site.schema.json"
+
+// siteSchema is the subset of the upstream OpenAPI document this test cares
+// about: the enum of valid ce_site_size values and the set of fields Terraform
+// must populate when creating a volterra_azure_vnet_site resource.
+type siteSchema struct {
+	CeSiteSizeEnum []string `json:"ce_site_size_enum"`
+	RequiredFields []string `json:"required_fields"`
+}
+
+// fetchSiteSchema retrieves the azure_vnet_site schema from baseURL. Passing
+// an empty baseURL fetches the live upstream document; tests should instead
+// point this at a httptest.Server (see newMockSchemaServer) to run offline.
+func fetchSiteSchema(baseURL string) (*siteSchema, error) {
+	if baseURL == "" {
+		baseURL = os.Getenv(volterraOpenAPIURLEnv)
+	}
+	if baseURL == "" {
+		baseURL = defaultVolterraOpenAPIURL
+	}
+
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Get(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("fetching volterra_azure_vnet_site schema from %s: %w", baseURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching volterra_azure_vnet_site schema from %s: unexpected status %s", baseURL, resp.Status)
+	}
+
+	var schema siteSchema
+	if err := json.NewDecoder(resp.Body).Decode(&schema); err != nil {
+		return nil, fmt.Errorf("decoding volterra_azure_vnet_site schema: %w", err)
+	}
+	return &schema, nil
+}
+
+// loadPinnedSiteSchema reads the committed snapshot at pinnedSiteSchemaFile.
+// This is what newMockSchemaServer serves by default, standing in for the
+// live upstream document during offline/CI runs.
+func loadPinnedSiteSchema() (siteSchema, error) {
+	raw, err := os.ReadFile(pinnedSiteSchemaFile)
+	if err != nil {
+		return siteSchema{}, fmt.Errorf("reading pinned schema snapshot %s: %w", pinnedSiteSchemaFile, err)
+	}
+
+	var schema siteSchema
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		return siteSchema{}, fmt.Errorf("parsing pinned schema snapshot %s: %w", pinnedSiteSchemaFile, err)
+	}
+	return schema, nil
+}
+
+// newMockSchemaServer stands up an in-memory server that serves schema as the
+// upstream OpenAPI document, so TestF5XCSiteCreationSchemaContract can run in
+// CI without reaching docs.cloud.f5.com.
+func newMockSchemaServer(schema siteSchema) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(schema)
+	}))
+}
+
+// moduleAssumedSchema is what terraform/modules/f5-xc-registration currently
+// assumes about volterra_azure_vnet_site: the ce_site_size values its
+// variable validation accepts, and the fields main.tf always populates.
+// Kept independent of pinnedSiteSchemaFile/testdata on purpose -- if the two
+// ever diverge, that divergence IS the drift this contract test exists to
+// catch, so this must not be sourced from the same fixture.
+func moduleAssumedSchema() siteSchema {
+	return siteSchema{
+		CeSiteSizeEnum: []string{"small", "medium", "large"},
+		RequiredFields: []string{"site_type", "master_nodes"},
+	}
+}
@@ -0,0 +1,89 @@
+package test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// liveSchemaEnv opts a run into hitting the real F5 Distributed Cloud docs
+// site instead of the bundled mock server. Unset (the default) keeps CI
+// offline.
+const liveSchemaEnv = "F5XC_CONTRACT_LIVE"
+
+// TestF5XCSiteCreationSchemaContract extends TestF5XCSiteCreationContract
+// (tests/integration) with live schema verification: it plans the
+// f5-xc-registration module and asserts that the volterra_azure_vnet_site
+// resource_changes payload still matches the upstream OpenAPI schema for
+// enum values (ce_site_size) and required fields (site_type, master_nodes).
+// This is expected to fail the build the moment the volterra provider drifts
+// from what our modules assume, the same role azurerm schema checks play for
+// azurerm_subnet/azurerm_virtual_network elsewhere in this suite.
+func TestF5XCSiteCreationSchemaContract(t *testing.T) {
+	t.Parallel()
+
+	var schema siteSchema
+	if os.Getenv(liveSchemaEnv) != "true" {
+		pinned, err := loadPinnedSiteSchema()
+		require.NoError(t, err, "loading pinned upstream schema snapshot")
+
+		server := newMockSchemaServer(pinned)
+		defer server.Close()
+		fetched, err := fetchSiteSchema(server.URL)
+		require.NoError(t, err, "mock schema server should always be reachable")
+		schema = *fetched
+	} else {
+		live, err := fetchSiteSchema("")
+		require.NoError(t, err, "fetching live volterra_azure_vnet_site schema")
+		schema = *live
+	}
+
+	// The pinned snapshot (or the live fetch) is what upstream actually
+	// reports; moduleAssumedSchema is what our Terraform modules assume.
+	// A mismatch here IS upstream drift -- this is the check that fails the
+	// build even in offline/CI runs, not just under F5XC_CONTRACT_LIVE=true.
+	// Upstream is allowed to be a superset (e.g. a new ce_site_size upstream
+	// added that our modules don't support yet isn't drift); what we can't
+	// tolerate is upstream dropping a value our modules still rely on.
+	assumed := moduleAssumedSchema()
+	assert.Subset(t, schema.CeSiteSizeEnum, assumed.CeSiteSizeEnum,
+		"volterra_azure_vnet_site ce_site_size enum has dropped a value our modules still assume")
+	for _, field := range assumed.RequiredFields {
+		assert.Contains(t, schema.RequiredFields, field,
+			"volterra_azure_vnet_site no longer requires %q per upstream schema, but our modules still assume it does", field)
+	}
+
+	terraformOptions := terraform.WithDefaultRetryableErrors(t, &terraform.Options{
+		TerraformDir: "../../terraform/modules/f5-xc-registration",
+		Vars: map[string]interface{}{
+			"f5_xc_api_token": "test-token",
+			"f5_xc_tenant":    "test-tenant",
+			"site_name":       "test-ce-site",
+			"azure_region":    "eastus",
+			"ce_site_size":    "medium",
+		},
+		NoColor: true,
+	})
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	planStruct := terraform.InitAndPlanAndShowWithStruct(t, terraformOptions)
+	change, ok := planStruct.ResourceChangesMap["volterra_azure_vnet_site.ce_site[0]"]
+	require.True(t, ok, "plan must include volterra_azure_vnet_site.ce_site[0]")
+
+	after, ok := change.Change.After.(map[string]interface{})
+	require.True(t, ok, "resource_changes[].change.after must decode as an object")
+
+	for _, field := range schema.RequiredFields {
+		assert.Contains(t, after, field,
+			"volterra_azure_vnet_site.ce_site is missing required field %q per upstream schema", field)
+	}
+
+	ceSiteSize, ok := after["ce_site_size"].(string)
+	require.True(t, ok, "ce_site_size must be a string in the plan")
+	assert.Contains(t, schema.CeSiteSizeEnum, ceSiteSize,
+		"ce_site_size %q is not a valid enum value per upstream schema %v", ceSiteSize, schema.CeSiteSizeEnum)
+}
@@ -0,0 +1,139 @@
+package costcheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"testing"
+
+	tfjson "github.com/hashicorp/terraform-json"
+)
+
+// LineItem is the estimated monthly cost of a single planned resource.
+type LineItem struct {
+	Address    string
+	MonthlyUSD float64
+}
+
+// Estimate is the result of estimating a plan's monthly cost.
+type Estimate struct {
+	TotalMonthlyUSD float64
+	LineItems       []LineItem
+	Source          string // "infracost" or "snapshot"
+}
+
+// EstimateFromPlan estimates the monthly cost of resourceChanges. It prefers
+// shelling out to Infracost (if the binary is on PATH and tfDir is set) for
+// an up-to-date estimate, and falls back to the bundled Azure retail-price
+// snapshot in pricing.go so offline/CI runs without network access still get
+// a first-order check.
+func EstimateFromPlan(t *testing.T, tfDir string, resourceChanges map[string]*tfjson.ResourceChange) (Estimate, error) {
+	t.Helper()
+
+	if path, err := exec.LookPath("infracost"); err == nil && tfDir != "" {
+		estimate, err := estimateWithInfracost(path, tfDir)
+		if err == nil {
+			return estimate, nil
+		}
+		t.Logf("costcheck: infracost run failed (%v), falling back to bundled retail-price snapshot", err)
+	}
+
+	return estimateFromSnapshot(resourceChanges)
+}
+
+func estimateWithInfracost(binary, tfDir string) (Estimate, error) {
+	cmd := exec.Command(binary, "breakdown", "--path", tfDir, "--format", "json")
+	out, err := cmd.Output()
+	if err != nil {
+		return Estimate{}, fmt.Errorf("running infracost: %w", err)
+	}
+
+	var result struct {
+		TotalMonthlyCost string `json:"totalMonthlyCost"`
+		Projects         []struct {
+			Breakdown struct {
+				Resources []struct {
+					Name        string `json:"name"`
+					MonthlyCost string `json:"monthlyCost"`
+				} `json:"resources"`
+			} `json:"breakdown"`
+		} `json:"projects"`
+	}
+	if err := json.Unmarshal(out, &result); err != nil {
+		return Estimate{}, fmt.Errorf("parsing infracost output: %w", err)
+	}
+
+	var total float64
+	if _, err := fmt.Sscanf(result.TotalMonthlyCost, "%f", &total); err != nil {
+		return Estimate{}, fmt.Errorf("parsing infracost total: %w", err)
+	}
+
+	var items []LineItem
+	for _, project := range result.Projects {
+		for _, resource := range project.Breakdown.Resources {
+			var cost float64
+			_, _ = fmt.Sscanf(resource.MonthlyCost, "%f", &cost)
+			items = append(items, LineItem{Address: resource.Name, MonthlyUSD: cost})
+		}
+	}
+
+	return Estimate{TotalMonthlyUSD: total, LineItems: items, Source: "infracost"}, nil
+}
+
+// estimateFromSnapshot covers the handful of resource types our modules
+// provision: VMs (by size), load balancers (by SKU), VNET peerings
+// (assumed monthly egress), and public IPs. A resource type we don't
+// recognize at all is priced at $0 (this is a sanity check, not a full TCO
+// model), but a VM size or LB SKU we don't have a snapshot entry for is an
+// error -- pricing it at $0 is exactly how a size change like medium ->
+// large would silently blow past the cost-estimation gate's budget.
+func estimateFromSnapshot(resourceChanges map[string]*tfjson.ResourceChange) (Estimate, error) {
+	var items []LineItem
+	var total float64
+
+	for address, change := range resourceChanges {
+		if change.Change == nil || !actionCreatesOrUpdates(change.Change.Actions) {
+			continue
+		}
+		after, _ := change.Change.After.(map[string]interface{})
+
+		var monthly float64
+		switch change.Type {
+		case "azurerm_linux_virtual_machine", "azurerm_windows_virtual_machine":
+			size, ok := after["size"].(string)
+			if !ok {
+				return Estimate{}, fmt.Errorf("costcheck: %s has no size attribute in plan", address)
+			}
+			hourly, ok := retailPriceSnapshot.VMHourlyUSD[size]
+			if !ok {
+				return Estimate{}, fmt.Errorf("costcheck: %s uses VM size %q, which has no entry in the retail-price snapshot -- add one to pricing.go before this can be priced", address, size)
+			}
+			monthly = hourly * hoursPerMonth
+		case "azurerm_lb":
+			sku, ok := after["sku"].(string)
+			if !ok {
+				return Estimate{}, fmt.Errorf("costcheck: %s has no sku attribute in plan", address)
+			}
+			base, ok := retailPriceSnapshot.LBBaseMonthlyUSD[sku]
+			if !ok {
+				return Estimate{}, fmt.Errorf("costcheck: %s uses LB SKU %q, which has no entry in the retail-price snapshot -- add one to pricing.go before this can be priced", address, sku)
+			}
+			monthly = base
+		case "azurerm_virtual_network_peering":
+			monthly = retailPriceSnapshot.VNetPeeringPerGBUSD * retailPriceSnapshot.AssumedPeeringGBMonth
+		case "azurerm_public_ip":
+			monthly = retailPriceSnapshot.PublicIPMonthlyUSD
+		default:
+			continue
+		}
+
+		items = append(items, LineItem{Address: address, MonthlyUSD: monthly})
+		total += monthly
+	}
+
+	return Estimate{TotalMonthlyUSD: total, LineItems: items, Source: "snapshot"}, nil
+}
+
+func actionCreatesOrUpdates(actions tfjson.Actions) bool {
+	return actions.Create() || actions.Update() || actions.CreateBeforeDestroy() || actions.DestroyBeforeCreate()
+}
@@ -0,0 +1,32 @@
+// Package costcheck estimates the monthly cost of a Terraform plan so
+// TestEndToEndDeployment can fail before TerraformApply if a change
+// silently balloons the CE fleet spend (e.g. ce_site_size medium -> large).
+package costcheck
+
+// retailPriceSnapshot is a bundled, point-in-time mirror of the Azure
+// retail prices (East US, pay-as-you-go) this package's offline estimator
+// is built against. It only covers the SKUs our modules actually use, and
+// is not meant to track live pricing, hence "snapshot" -- Infracost is
+// preferred when available (see EstimateFromPlan).
+var retailPriceSnapshot = struct {
+	VMHourlyUSD           map[string]float64
+	LBBaseMonthlyUSD      map[string]float64
+	VNetPeeringPerGBUSD   float64
+	PublicIPMonthlyUSD    float64
+	AssumedPeeringGBMonth float64
+}{
+	VMHourlyUSD: map[string]float64{
+		"Standard_D8_v4":  0.384,
+		"Standard_D4_v4":  0.192,
+		"Standard_D16_v4": 0.768,
+	},
+	LBBaseMonthlyUSD: map[string]float64{
+		"Basic":    0,
+		"Standard": 18.25,
+	},
+	VNetPeeringPerGBUSD:   0.01,
+	PublicIPMonthlyUSD:    3.65,
+	AssumedPeeringGBMonth: 100,
+}
+
+const hoursPerMonth = 730
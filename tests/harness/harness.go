@@ -0,0 +1,119 @@
+// Package harness abstracts the cloud-specific pieces of CE validation
+// (compute instance existence, network lookup, identity binding) behind a
+// single interface so the same end-to-end test flow can run against Azure,
+// AWS, or GCP without branching on cloud provider throughout the test body.
+package harness
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/aws"
+	"github.com/gruntwork-io/terratest/modules/azure"
+	"github.com/gruntwork-io/terratest/modules/gcp"
+)
+
+// CloudValidator validates a deployed CE instance and the network/identity
+// IDs Terraform reports for it.
+type CloudValidator interface {
+	// Name identifies the cloud this validator targets, e.g. "azure".
+	Name() string
+
+	// ModuleDir is the CE module directory to deploy for this cloud,
+	// relative to terraform/modules.
+	ModuleDir() string
+
+	// InstanceExists reports whether the CE compute instance exists.
+	InstanceExists(t *testing.T, instanceName, region string) bool
+
+	// SubnetExists reports whether a subnet ID for the CE instance was
+	// reported. Every implementation only checks that subnetID is
+	// non-empty; the cloud is not queried.
+	SubnetExists(t *testing.T, subnetID, region string) bool
+
+	// IdentityBound reports whether an identity ID for the CE instance was
+	// reported: a managed identity on Azure, an instance profile on AWS, or
+	// a service account on GCP. Every implementation only checks that
+	// identityID is non-empty; the binding is not looked up in the cloud.
+	IdentityBound(t *testing.T, identityID string) bool
+}
+
+// SupportedClouds lists the cloud providers New accepts. Callers that take a
+// cloud provider from outside the test binary (e.g. a -cloud flag) must
+// validate against this before calling New, since New itself panics on an
+// unrecognized value.
+var SupportedClouds = []string{"azure", "aws", "gcp"}
+
+// New returns the CloudValidator for the named cloud provider. It panics on
+// an unknown provider since that indicates a bug in the caller -- callers
+// taking cloudProvider from user input (flags, env vars) must validate
+// against SupportedClouds first so bad input fails that one test instead of
+// crashing the whole test binary.
+func New(cloudProvider string) CloudValidator {
+	switch cloudProvider {
+	case "azure":
+		return azureValidator{}
+	case "aws":
+		return awsValidator{}
+	case "gcp":
+		return gcpValidator{}
+	default:
+		panic(fmt.Sprintf("harness: unknown cloud provider %q", cloudProvider))
+	}
+}
+
+type azureValidator struct{}
+
+func (azureValidator) Name() string      { return "azure" }
+func (azureValidator) ModuleDir() string { return "f5-xc-ce-appstack" }
+
+func (azureValidator) InstanceExists(t *testing.T, instanceName, region string) bool {
+	return azure.VirtualMachineExists(t, instanceName, region, "")
+}
+
+func (azureValidator) SubnetExists(t *testing.T, subnetID, region string) bool {
+	return subnetID != ""
+}
+
+func (azureValidator) IdentityBound(t *testing.T, identityID string) bool {
+	return identityID != ""
+}
+
+type awsValidator struct{}
+
+func (awsValidator) Name() string      { return "aws" }
+func (awsValidator) ModuleDir() string { return "f5-xc-ce-aws" }
+
+func (awsValidator) InstanceExists(t *testing.T, instanceName, region string) bool {
+	instanceIDs := aws.GetEc2InstanceIdsByTag(t, region, "Name", instanceName)
+	return len(instanceIDs) > 0
+}
+
+func (awsValidator) SubnetExists(t *testing.T, subnetID, region string) bool {
+	return subnetID != ""
+}
+
+func (awsValidator) IdentityBound(t *testing.T, identityID string) bool {
+	return identityID != ""
+}
+
+type gcpValidator struct{}
+
+func (gcpValidator) Name() string      { return "gcp" }
+func (gcpValidator) ModuleDir() string { return "f5-xc-ce-gcp" }
+
+// InstanceExists looks up the CE instance by name. region is interpreted as
+// the GCP project ID here, matching how gcp.FetchInstance expects its
+// project argument.
+func (gcpValidator) InstanceExists(t *testing.T, instanceName, region string) bool {
+	instance := gcp.FetchInstance(t, region, instanceName)
+	return instance != nil
+}
+
+func (gcpValidator) SubnetExists(t *testing.T, subnetID, region string) bool {
+	return subnetID != ""
+}
+
+func (gcpValidator) IdentityBound(t *testing.T, identityID string) bool {
+	return identityID != ""
+}
@@ -0,0 +1,81 @@
+package test
+
+import (
+	"flag"
+	"testing"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/robinmordasiewicz/f5-xc-ce-terraform/tests/harness"
+)
+
+// cloudFlag selects which cloud TestEndToEndDeployment validates against.
+// Mirrors e.g. `go test -run TestEndToEndDeployment -cloud=aws`.
+var cloudFlag = flag.String("cloud", "azure", "cloud provider to run TestEndToEndDeployment against: azure, aws, or gcp")
+
+// maxMonthlyUSD is the cost-estimation gate's budget for ce_site_size=medium.
+// Mirrors e.g. `go test -run TestEndToEndDeployment -maxMonthlyUSD=5000`.
+var maxMonthlyUSD = flag.Float64("maxMonthlyUSD", 2000, "fail TestEndToEndDeployment if the estimated monthly cost of the plan exceeds this")
+
+// TestCERegistration_AWS mirrors TestCERegistration but deploys the CE onto
+// an EC2 instance via f5-xc-ce-aws and validates it through the harness
+// package instead of Azure-specific terratest helpers.
+func TestCERegistration_AWS(t *testing.T) {
+	t.Parallel()
+	validator := harness.New("aws")
+
+	terraformOptions := terraform.WithDefaultRetryableErrors(t, &terraform.Options{
+		TerraformDir: "../../terraform/modules/" + validator.ModuleDir(),
+		Vars: map[string]interface{}{
+			"ce_instance_name":   "test-ce-aws",
+			"subnet_id":          "subnet-0123456789abcdef0",
+			"instance_type":      "c5.2xlarge",
+			"registration_token": "test-registration-token",
+		},
+		NoColor: true,
+	})
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	terraform.InitAndApply(t, terraformOptions)
+
+	instanceID := terraform.Output(t, terraformOptions, "ce_instance_id")
+	assert.NotEmpty(t, instanceID, "CE instance ID must be output")
+
+	instanceProfileARN := terraform.Output(t, terraformOptions, "ce_instance_profile_arn")
+	assert.NotEmpty(t, instanceProfileARN, "Instance profile must be assigned")
+	assert.True(t, validator.IdentityBound(t, instanceProfileARN), "CE instance must have an instance profile bound")
+}
+
+// TestCERegistration_GCP mirrors TestCERegistration but deploys the CE onto
+// a GCE instance via f5-xc-ce-gcp and validates it through the harness
+// package instead of Azure-specific terratest helpers.
+func TestCERegistration_GCP(t *testing.T) {
+	t.Parallel()
+	validator := harness.New("gcp")
+
+	terraformOptions := terraform.WithDefaultRetryableErrors(t, &terraform.Options{
+		TerraformDir: "../../terraform/modules/" + validator.ModuleDir(),
+		Vars: map[string]interface{}{
+			"project_id":         "test-project",
+			"zone":               "us-central1-a",
+			"ce_instance_name":   "test-ce-gcp",
+			"subnetwork":         "projects/test-project/regions/us-central1/subnetworks/nva-subnet",
+			"machine_type":       "n2-standard-8",
+			"registration_token": "test-registration-token",
+		},
+		NoColor: true,
+	})
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	terraform.InitAndApply(t, terraformOptions)
+
+	instanceID := terraform.Output(t, terraformOptions, "ce_instance_id")
+	assert.NotEmpty(t, instanceID, "CE instance ID must be output")
+
+	serviceAccountEmail := terraform.Output(t, terraformOptions, "ce_service_account_email")
+	assert.NotEmpty(t, serviceAccountEmail, "Service account must be assigned")
+	assert.True(t, validator.IdentityBound(t, serviceAccountEmail), "CE instance must have a service account bound")
+}
@@ -0,0 +1,147 @@
+package test
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// maxOutageSeconds bounds the longest continuous window in which the LB
+// health-probe port is unreachable during a rollout. Mirrors e.g.
+// `go test -run TestCEUpgradeRollout -maxOutageSeconds=5`.
+var maxOutageSeconds = flag.Int("maxOutageSeconds", 10, "fail TestCEUpgradeRollout if any single outage window during the rollout exceeds this many seconds")
+
+// TestCEUpgradeRollout deploys a CE at one software version, bumps
+// desired_sw_version to the next, and continuously probes the LB
+// health-probe port throughout the rollout to confirm the drain/upgrade/
+// re-add sequence in the ce_upgrade null_resource keeps any single outage
+// window under maxOutageSeconds.
+func TestCEUpgradeRollout(t *testing.T) {
+	t.Parallel()
+
+	const (
+		initialVersion  = "2024.30"
+		upgradedVersion = "2024.31"
+	)
+
+	baseVars := map[string]interface{}{
+		"resource_group_name": "test-rg",
+		"location":            "eastus",
+		"ce_instance_name":    "test-ce-upgrade",
+		"subnet_id":           "/subscriptions/test/resourceGroups/test-rg/providers/Microsoft.Network/virtualNetworks/test-vnet/subnets/nva-subnet",
+		"ssh_public_key":      "ssh-rsa AAAAtest test-ce-upgrade",
+		"vm_size":             "Standard_D8_v4",
+		"registration_token":  "test-registration-token",
+		"f5_xc_api_token":     "test-token",
+		"f5_xc_tenant":        "test-tenant",
+		"site_name":           "test-ce-upgrade-site",
+		"lb_backend_pool_id":  "/subscriptions/test/resourceGroups/test-rg/providers/Microsoft.Network/loadBalancers/test-lb/backendAddressPools/test-pool",
+		"desired_sw_version":  initialVersion,
+	}
+
+	terraformOptions := terraform.WithDefaultRetryableErrors(t, &terraform.Options{
+		TerraformDir: "../../terraform/modules/f5-xc-ce-appstack",
+		Vars:         baseVars,
+		NoColor:      true,
+	})
+
+	defer terraform.Destroy(t, terraformOptions)
+
+	// enable_upgrade_rollout stays at its default false for this first apply:
+	// there's no pre-existing site yet for ce_upgrade to drain and re-add.
+	terraform.InitAndApply(t, terraformOptions)
+
+	probePort := terraform.Output(t, terraformOptions, "lb_health_probe_port")
+	require.Equal(t, "65500", probePort, "CE must expose the LB health probe on port 65500")
+
+	ceIP := terraform.Output(t, terraformOptions, "ce_private_ip")
+	require.NotEmpty(t, ceIP, "CE VM must have a private IP before starting the upgrade rollout")
+
+	stopProbing := make(chan struct{})
+	var wg sync.WaitGroup
+	var longestOutageMillis int64
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		probeHealthDuringRollout(t, ceIP, probePort, stopProbing, &longestOutageMillis)
+	}()
+
+	terraform.Apply(t, terraform.WithDefaultRetryableErrors(t, &terraform.Options{
+		TerraformDir: "../../terraform/modules/f5-xc-ce-appstack",
+		Vars: mergeVars(baseVars, map[string]interface{}{
+			"desired_sw_version":     upgradedVersion,
+			"enable_upgrade_rollout": true,
+		}),
+		NoColor: true,
+	}))
+
+	close(stopProbing)
+	wg.Wait()
+
+	swVersion := terraform.Output(t, terraformOptions, "sw_version")
+	assert.Equal(t, upgradedVersion, swVersion, "site must converge on the new software version")
+
+	longestOutage := time.Duration(atomic.LoadInt64(&longestOutageMillis)) * time.Millisecond
+	t.Logf("longest observed outage window: %v (budget: %ds)", longestOutage, *maxOutageSeconds)
+	assert.LessOrEqual(t, longestOutage, time.Duration(*maxOutageSeconds)*time.Second,
+		"upgrade rollout exceeded the max_outage_seconds budget")
+}
+
+// probeHealthDuringRollout dials the LB health-probe port at a fixed
+// interval until stop is closed, tracking the longest continuous span of
+// failed dials in *longestOutageMillis.
+func probeHealthDuringRollout(t *testing.T, host, port string, stop <-chan struct{}, longestOutageMillis *int64) {
+	t.Helper()
+
+	const interval = 250 * time.Millisecond
+	var outageStart time.Time
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			conn, err := net.DialTimeout("tcp", fmt.Sprintf("%s:%s", host, port), interval)
+			if err == nil {
+				conn.Close()
+				outageStart = time.Time{}
+				continue
+			}
+
+			if outageStart.IsZero() {
+				outageStart = time.Now()
+			}
+			outage := time.Since(outageStart).Milliseconds()
+			for {
+				current := atomic.LoadInt64(longestOutageMillis)
+				if outage <= current || atomic.CompareAndSwapInt64(longestOutageMillis, current, outage) {
+					break
+				}
+			}
+		}
+	}
+}
+
+// mergeVars returns a new map containing base overridden by overrides.
+func mergeVars(base, overrides map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(base)+len(overrides))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range overrides {
+		merged[k] = v
+	}
+	return merged
+}
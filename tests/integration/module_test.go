@@ -1,14 +1,19 @@
 package test
 
 import (
+	"context"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
-	"github.com/gruntwork-io/terratest/modules/azure"
 	"github.com/gruntwork-io/terratest/modules/terraform"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/robinmordasiewicz/f5-xc-ce-terraform/tests/azureclient"
+	"github.com/robinmordasiewicz/f5-xc-ce-terraform/tests/costcheck"
+	"github.com/robinmordasiewicz/f5-xc-ce-terraform/tests/harness"
 )
 
 // T027: Contract test for F5 XC site creation API
@@ -32,7 +37,7 @@ func TestF5XCSiteCreationContract(t *testing.T) {
 	terraform.InitAndPlan(t, terraformOptions)
 
 	// Validate that plan includes volterra_azure_vnet_site resource
-	planStruct := terraform.InitAndPlanAndShow(t, terraformOptions)
+	planStruct := terraform.InitAndPlanAndShowWithStruct(t, terraformOptions)
 	resourceChanges := planStruct.ResourceChangesMap
 
 	// Assert F5 XC site resource will be created
@@ -56,6 +61,7 @@ func TestCERegistration(t *testing.T) {
 			"location":            "eastus",
 			"ce_instance_name":    "test-ce",
 			"subnet_id":           "/subscriptions/test/resourceGroups/test-rg/providers/Microsoft.Network/virtualNetworks/test-vnet/subnets/nva-subnet",
+			"ssh_public_key":      "ssh-rsa AAAAtest test-ce",
 			"vm_size":             "Standard_D8_v4",
 			"registration_token":  "test-registration-token",
 		},
@@ -118,13 +124,13 @@ func TestNetworkRoutingValidation(t *testing.T) {
 	spokeOptions := terraform.WithDefaultRetryableErrors(t, &terraform.Options{
 		TerraformDir: "../../terraform/modules/azure-spoke-vnet",
 		Vars: map[string]interface{}{
-			"resource_group_name": "test-routing-rg",
-			"location":            "eastus",
-			"vnet_name":           "test-spoke-vnet",
-			"address_space":       []string{"10.1.0.0/16"},
+			"resource_group_name":    "test-routing-rg",
+			"location":               "eastus",
+			"vnet_name":              "test-spoke-vnet",
+			"address_space":          []string{"10.1.0.0/16"},
 			"workload_subnet_prefix": "10.1.1.0/24",
-			"hub_vnet_id":         hubVnetID,
-			"hub_nva_ip":          "10.0.1.4",
+			"hub_vnet_id":            hubVnetID,
+			"hub_nva_ip":             "10.0.1.4",
 			"tags": map[string]string{
 				"environment": "test",
 				"managed_by":  "terraform",
@@ -138,6 +144,10 @@ func TestNetworkRoutingValidation(t *testing.T) {
 	// TDD: This test will FAIL until spoke VNET module is implemented
 	terraform.InitAndApply(t, spokeOptions)
 
+	// Route-table association can still be converging even though Terraform
+	// reports apply as complete; wait for it before trusting route_table_id.
+	waitForSubnetReady(t, azureSubscriptionIDForTests(), "test-routing-rg", "test-spoke-vnet", "workload-subnet", 5*time.Minute)
+
 	spokeVnetID := terraform.Output(t, spokeOptions, "vnet_id")
 	peeringID := terraform.Output(t, spokeOptions, "peering_id")
 	routeTableID := terraform.Output(t, spokeOptions, "route_table_id")
@@ -162,22 +172,44 @@ func TestEndToEndDeployment(t *testing.T) {
 	// Not parallel - full integration test
 	// This is the comprehensive E2E test that validates entire User Story 1
 
-	t.Log("Starting end-to-end deployment test for User Story 1...")
+	cloud := *cloudFlag
+	if !slices.Contains(harness.SupportedClouds, cloud) {
+		t.Fatalf("unsupported -cloud %q: must be one of %v", cloud, harness.SupportedClouds)
+	}
+	validator := harness.New(cloud)
+	t.Logf("Starting end-to-end deployment test for User Story 1 (cloud=%s)...", cloud)
+
+	// instanceLookupScope is what harness.CloudValidator.InstanceExists needs
+	// as its second argument: a resource group on Azure, a region on AWS, a
+	// project ID on GCP. Each cloud's branch below fills this in; it must
+	// never be left as the Azure-only resourceGroup for aws/gcp.
+	var instanceLookupScope string
+	switch cloud {
+	case "aws":
+		instanceLookupScope = "us-east-1"
+	case "gcp":
+		instanceLookupScope = "test-project"
+	}
+
+	azureClient, err := azureclient.ConfigFromEnvironment().NewClient()
+	require.NoError(t, err, "building Azure client")
+	t.Logf("azureclient: authenticating via %s", azureClient.AuthMode)
 
 	terraformOptions := terraform.WithDefaultRetryableErrors(t, &terraform.Options{
 		TerraformDir: "../../terraform/environments/dev",
 		Vars: map[string]interface{}{
-			"azure_region":           "eastus",
-			"resource_group_name":    "xc-ce-test-rg",
-			"hub_vnet_address_space": []string{"10.0.0.0/16"},
+			"cloud_provider":           cloud,
+			"azure_region":             "eastus",
+			"resource_group_name":      "xc-ce-test-rg",
+			"hub_vnet_address_space":   []string{"10.0.0.0/16"},
 			"spoke_vnet_address_space": []string{"10.1.0.0/16"},
-			"ce_site_size":           "medium",
+			"ce_site_size":             "medium",
 			"tags": map[string]string{
 				"environment": "test",
 				"managed_by":  "terraform",
 			},
 		},
-		NoColor:      true,
+		NoColor: true,
 		RetryableTerraformErrors: map[string]string{
 			".*timeout while waiting.*": "Azure resource creation timeout",
 		},
@@ -189,38 +221,56 @@ func TestEndToEndDeployment(t *testing.T) {
 
 	// Step 1: TDD - This test will FAIL until all modules are implemented
 	t.Log("Step 1/8: Running terraform init and plan...")
-	terraform.InitAndPlan(t, terraformOptions)
+	planStruct := terraform.InitAndPlanAndShowWithStruct(t, terraformOptions)
+
+	// Cost gate: refuse to apply a plan that would silently balloon spend,
+	// e.g. a ce_site_size change from medium to large.
+	t.Log("Running cost-estimation gate before apply...")
+	estimate, err := costcheck.EstimateFromPlan(t, terraformOptions.TerraformDir, planStruct.ResourceChangesMap)
+	require.NoError(t, err, "cost estimation must succeed")
+	for _, item := range estimate.LineItems {
+		t.Logf("costcheck[%s]: %s ~ $%.2f/mo", estimate.Source, item.Address, item.MonthlyUSD)
+	}
+	t.Logf("costcheck: estimated total $%.2f/mo (source=%s, budget=$%.2f/mo)", estimate.TotalMonthlyUSD, estimate.Source, *maxMonthlyUSD)
+	require.LessOrEqualf(t, estimate.TotalMonthlyUSD, *maxMonthlyUSD,
+		"estimated monthly cost $%.2f exceeds max_monthly_usd $%.2f", estimate.TotalMonthlyUSD, *maxMonthlyUSD)
 
 	// Step 2: Apply infrastructure
 	t.Log("Step 2/8: Applying Terraform configuration...")
 	terraform.Apply(t, terraformOptions)
 
-	// Step 3: Validate hub VNET created
-	t.Log("Step 3/8: Validating hub VNET...")
-	hubVnetID := terraform.Output(t, terraformOptions, "hub_vnet_id")
-	require.NotEmpty(t, hubVnetID, "Hub VNET ID must exist")
-
-	// Parse resource group from VNET ID
-	resourceGroup := extractResourceGroup(hubVnetID)
-	hubVnetName := terraform.Output(t, terraformOptions, "hub_vnet_name")
-
-	// Verify hub VNET exists in Azure
-	hubVnetExists := azure.VirtualNetworkExists(t, hubVnetName, resourceGroup, "")
-	assert.True(t, hubVnetExists, "Hub VNET must exist in Azure")
-
-	// Step 4: Validate spoke VNET created
-	t.Log("Step 4/8: Validating spoke VNET...")
-	spokeVnetID := terraform.Output(t, terraformOptions, "spoke_vnet_id")
-	require.NotEmpty(t, spokeVnetID, "Spoke VNET ID must exist")
-
-	spokeVnetName := terraform.Output(t, terraformOptions, "spoke_vnet_name")
-	spokeVnetExists := azure.VirtualNetworkExists(t, spokeVnetName, resourceGroup, "")
-	assert.True(t, spokeVnetExists, "Spoke VNET must exist in Azure")
-
-	// Step 5: Validate VNET peering established
-	t.Log("Step 5/8: Validating VNET peering...")
-	peeringStatus := terraform.Output(t, terraformOptions, "peering_status")
-	assert.Equal(t, "Connected", peeringStatus, "VNET peering must be in Connected state")
+	var resourceGroup string
+	if cloud == "azure" {
+		// Step 3: Validate hub VNET created
+		t.Log("Step 3/8: Validating hub VNET...")
+		hubVnetID := terraform.Output(t, terraformOptions, "hub_vnet_id")
+		require.NotEmpty(t, hubVnetID, "Hub VNET ID must exist")
+
+		// Parse resource group from VNET ID
+		resourceGroup = extractResourceGroup(hubVnetID)
+		instanceLookupScope = resourceGroup
+
+		// Verify hub VNET exists in Azure
+		hubVnetExists, err := azureClient.ResourceExists(context.Background(), hubVnetID, azureAPIVersion)
+		require.NoError(t, err, "checking whether hub VNET exists")
+		assert.True(t, hubVnetExists, "Hub VNET must exist in Azure")
+
+		// Step 4: Validate spoke VNET created
+		t.Log("Step 4/8: Validating spoke VNET...")
+		spokeVnetID := terraform.Output(t, terraformOptions, "spoke_vnet_id")
+		require.NotEmpty(t, spokeVnetID, "Spoke VNET ID must exist")
+
+		spokeVnetExists, err := azureClient.ResourceExists(context.Background(), spokeVnetID, azureAPIVersion)
+		require.NoError(t, err, "checking whether spoke VNET exists")
+		assert.True(t, spokeVnetExists, "Spoke VNET must exist in Azure")
+
+		// Step 5: Validate VNET peering established
+		t.Log("Step 5/8: Validating VNET peering...")
+		peeringStatus := terraform.Output(t, terraformOptions, "peering_status")
+		assert.Equal(t, "Connected", peeringStatus, "VNET peering must be in Connected state")
+	} else {
+		t.Logf("Steps 3-5/8: skipping hub/spoke VNET peering validation for cloud=%s (Azure-only topology)", cloud)
+	}
 
 	// Step 6: Validate CE registered with F5 XC Console
 	t.Log("Step 6/8: Validating CE registration...")
@@ -229,25 +279,28 @@ func TestEndToEndDeployment(t *testing.T) {
 	assert.NotEmpty(t, ceSiteName, "CE site name must be output")
 	assert.NotEmpty(t, ceSiteID, "CE site ID must be output")
 
-	// Validate CE VM exists
-	ceVMName := terraform.Output(t, terraformOptions, "ce_vm_name")
-	ceVMExists := azure.VirtualMachineExists(t, ceVMName, resourceGroup, "")
-	assert.True(t, ceVMExists, "CE VM must exist in Azure")
-
-	// Step 7: Validate routing through hub NVA
-	t.Log("Step 7/8: Validating routing configuration...")
-	defaultRouteNextHop := terraform.Output(t, terraformOptions, "default_route_next_hop")
-	assert.NotEmpty(t, defaultRouteNextHop, "Default route next hop must be configured")
-	assert.True(t, strings.HasPrefix(defaultRouteNextHop, "10.0."),
-		"Default route should point to hub subnet IP")
-
-	// Step 8: Validate load balancer health probes
-	t.Log("Step 8/8: Validating load balancer configuration...")
-	lbID := terraform.Output(t, terraformOptions, "load_balancer_id")
-	assert.NotEmpty(t, lbID, "Load balancer ID must be output")
-
-	lbHealthProbePort := terraform.Output(t, terraformOptions, "lb_health_probe_port")
-	assert.Equal(t, "65500", lbHealthProbePort, "Health probe should use port 65500")
+	// Validate CE instance exists, via the cloud-specific validator
+	ceInstanceName := terraform.Output(t, terraformOptions, "ce_vm_name")
+	assert.True(t, validator.InstanceExists(t, ceInstanceName, instanceLookupScope), "CE instance must exist")
+
+	if cloud == "azure" {
+		// Step 7: Validate routing through hub NVA
+		t.Log("Step 7/8: Validating routing configuration...")
+		defaultRouteNextHop := terraform.Output(t, terraformOptions, "default_route_next_hop")
+		assert.NotEmpty(t, defaultRouteNextHop, "Default route next hop must be configured")
+		assert.True(t, strings.HasPrefix(defaultRouteNextHop, "10.0."),
+			"Default route should point to hub subnet IP")
+
+		// Step 8: Validate load balancer health probes
+		t.Log("Step 8/8: Validating load balancer configuration...")
+		lbID := terraform.Output(t, terraformOptions, "load_balancer_id")
+		assert.NotEmpty(t, lbID, "Load balancer ID must be output")
+
+		lbHealthProbePort := terraform.Output(t, terraformOptions, "lb_health_probe_port")
+		assert.Equal(t, "65500", lbHealthProbePort, "Health probe should use port 65500")
+	} else {
+		t.Logf("Steps 7-8/8: skipping hub-routed LB validation for cloud=%s", cloud)
+	}
 
 	t.Log("✅ End-to-end deployment test completed successfully!")
 }
@@ -263,11 +316,25 @@ func extractResourceGroup(resourceID string) string {
 	return ""
 }
 
-// Helper function for Azure resource validation
-func validateAzureResource(t *testing.T, resourceID string) {
+// azureAPIVersion is the ARM API version used for the generic resourceID
+// existence lookups below. Good enough for an existence check; helpers that
+// need type-specific fields should use a typed SDK client instead.
+const azureAPIVersion = "2021-04-01"
+
+// Helper function for Azure resource validation. When client is non-nil it
+// also confirms resourceID actually exists in Azure instead of only
+// checking the ID's shape.
+func validateAzureResource(t *testing.T, client *azureclient.Client, resourceID string) {
 	assert.NotEmpty(t, resourceID, "Azure resource ID should not be empty")
 	assert.Contains(t, resourceID, "/subscriptions/", "Resource ID must be fully qualified")
 	assert.Contains(t, resourceID, "/resourceGroups/", "Resource ID must contain resource group")
+
+	if client == nil {
+		return
+	}
+	exists, err := client.ResourceExists(context.Background(), resourceID, azureAPIVersion)
+	require.NoError(t, err, "checking whether %s exists", resourceID)
+	assert.True(t, exists, "%s must exist in Azure", resourceID)
 }
 
 // Helper function for F5 XC Console validation
@@ -277,16 +344,32 @@ func validateF5XCRegistration(t *testing.T, siteID string) {
 	assert.Len(t, siteID, 36, "F5 XC site ID should be UUID format")
 }
 
-// Helper function to validate subnet configuration
-func validateSubnetConfiguration(t *testing.T, subnetID string, expectedPrefix string) {
+// Helper function to validate subnet configuration. When client is non-nil
+// it also confirms the subnet exists in Azure.
+func validateSubnetConfiguration(t *testing.T, client *azureclient.Client, subnetID string, expectedPrefix string) {
 	assert.NotEmpty(t, subnetID, "Subnet ID should not be empty")
 	assert.Contains(t, subnetID, "/subnets/", "Resource ID must be a subnet")
+
+	if client == nil {
+		return
+	}
+	exists, err := client.ResourceExists(context.Background(), subnetID, azureAPIVersion)
+	require.NoError(t, err, "checking whether subnet %s exists", subnetID)
+	assert.True(t, exists, "subnet %s must exist in Azure", subnetID)
 }
 
-// Helper function to validate NSG rules
-func validateNSGRules(t *testing.T, nsgID string) {
+// Helper function to validate NSG rules. When client is non-nil it also
+// confirms the NSG exists in Azure.
+func validateNSGRules(t *testing.T, client *azureclient.Client, nsgID string) {
 	assert.NotEmpty(t, nsgID, "NSG ID should not be empty")
 	assert.Contains(t, nsgID, "/networkSecurityGroups/", "Resource ID must be an NSG")
+
+	if client == nil {
+		return
+	}
+	exists, err := client.ResourceExists(context.Background(), nsgID, azureAPIVersion)
+	require.NoError(t, err, "checking whether NSG %s exists", nsgID)
+	assert.True(t, exists, "NSG %s must exist in Azure", nsgID)
 }
 
 // Helper function to validate load balancer configuration
@@ -310,11 +393,21 @@ func extractResourceName(resourceID string) string {
 	return ""
 }
 
-// Helper function to validate peering status
-func validatePeeringStatus(t *testing.T, peeringID string, expectedState string) {
+// Helper function to validate peering status. When client is non-nil it
+// confirms via azureclient that the peering exists in Azure; the peering's
+// state is not read or compared with expectedState, which only appears in
+// log and failure messages.
+func validatePeeringStatus(t *testing.T, client *azureclient.Client, peeringID string, expectedState string) {
 	assert.NotEmpty(t, peeringID, "Peering ID should not be empty")
-	// Peering validation will be enhanced with Azure SDK calls in actual deployment
-	t.Logf("Peering ID: %s (expected state: %s)", peeringID, expectedState)
+
+	if client == nil {
+		t.Logf("Peering ID: %s (expected state: %s, no live client configured)", peeringID, expectedState)
+		return
+	}
+
+	exists, err := client.ResourceExists(context.Background(), peeringID, azureAPIVersion)
+	require.NoError(t, err, "checking whether peering %s exists", peeringID)
+	assert.True(t, exists, "peering %s must exist in Azure (expected state: %s)", peeringID, expectedState)
 }
 
 // Helper function to wait for resource readiness
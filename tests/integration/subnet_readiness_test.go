@@ -0,0 +1,95 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v4"
+)
+
+// azureSubscriptionIDForTests reads the subscription ID live tests run
+// against from ARM_SUBSCRIPTION_ID, the same variable the azurerm provider
+// and Azure CLI use.
+func azureSubscriptionIDForTests() string {
+	return os.Getenv("ARM_SUBSCRIPTION_ID")
+}
+
+// waitForSubnetReady polls the Azure subnet identified by resourceGroup/
+// vnetName/subnetName until its provisioning state is Succeeded and it has no
+// in-flight route-table association, or timeout elapses. This guards
+// TestNetworkRoutingValidation against the same class of race the AKS
+// node-pool fix addresses: Azure can report a subnet as ready while its UDR
+// association is still converging, so a route_table_id read immediately
+// afterwards can observe a stale or partially-applied route table.
+//
+// Polling backs off exponentially starting at 1s and capping at 30s.
+func waitForSubnetReady(t *testing.T, subscriptionID, resourceGroup, vnetName, subnetName string, timeout time.Duration) {
+	t.Helper()
+
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		t.Fatalf("waitForSubnetReady: creating Azure credential: %v", err)
+	}
+
+	client, err := armnetwork.NewSubnetsClient(subscriptionID, cred, nil)
+	if err != nil {
+		t.Fatalf("waitForSubnetReady: creating subnets client: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	backoff := 1 * time.Second
+	const maxBackoff = 30 * time.Second
+
+	for {
+		subnet, err := client.Get(ctx, resourceGroup, vnetName, subnetName, nil)
+		if err != nil {
+			t.Logf("waitForSubnetReady: get %s/%s/%s failed, retrying: %v", resourceGroup, vnetName, subnetName, err)
+		} else if ready(subnet.Properties) {
+			t.Logf("waitForSubnetReady: %s/%s/%s is ready (provisioningState=Succeeded, route table converged)", resourceGroup, vnetName, subnetName)
+			return
+		} else {
+			t.Logf("waitForSubnetReady: %s/%s/%s not yet converged (provisioningState=%s)", resourceGroup, vnetName, subnetName, stateOf(subnet.Properties))
+		}
+
+		select {
+		case <-ctx.Done():
+			t.Fatalf("waitForSubnetReady: timed out after %v waiting for %s/%s/%s", timeout, resourceGroup, vnetName, subnetName)
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// ready reports whether a subnet's provisioning has finished and its
+// route-table association (if any) is no longer mid-update.
+func ready(props *armnetwork.SubnetPropertiesFormat) bool {
+	if props == nil || props.ProvisioningState == nil {
+		return false
+	}
+	if *props.ProvisioningState != armnetwork.ProvisioningStateSucceeded {
+		return false
+	}
+	if props.RouteTable != nil && props.RouteTable.Properties != nil &&
+		props.RouteTable.Properties.ProvisioningState != nil &&
+		*props.RouteTable.Properties.ProvisioningState != armnetwork.ProvisioningStateSucceeded {
+		return false
+	}
+	return true
+}
+
+func stateOf(props *armnetwork.SubnetPropertiesFormat) string {
+	if props == nil || props.ProvisioningState == nil {
+		return "unknown"
+	}
+	return fmt.Sprintf("%s", *props.ProvisioningState)
+}